@@ -0,0 +1,211 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var errArchiveReadOnly = errors.New("archive is read-only")
+
+// ArchiveExtensions lists the extensions OpenArchive recognizes. The
+// "Open File..." dialog's filter does not read this list; it matches
+// archives through IsArchiveFile.
+var ArchiveExtensions = []string{".zip", ".tar", ".tar.gz", ".tar.bz2"}
+
+type archiveKind int
+
+const (
+	archiveKindNone archiveKind = iota
+	archiveKindZip
+	archiveKindTar
+	archiveKindTarGz
+	archiveKindTarBz2
+)
+
+// IsArchiveFile reports whether path looks like a supported archive based
+// on its extension.
+func IsArchiveFile(path string) bool {
+	return detectArchiveKind(path) != archiveKindNone
+}
+
+// detectArchiveKind classifies path by its extension, falling back to a
+// double-extension check (".tar.gz", ".tar.bz2") for compressed tarballs.
+func detectArchiveKind(path string) archiveKind {
+	lower := strings.ToLower(path)
+	ext := strings.ToLower(filepath.Ext(lower))
+
+	switch ext {
+	case ".zip":
+		return archiveKindZip
+	case ".tar":
+		return archiveKindTar
+	case ".gz":
+		if strings.ToLower(filepath.Ext(strings.TrimSuffix(lower, ext))) == ".tar" {
+			return archiveKindTarGz
+		}
+	case ".bz2":
+		if strings.ToLower(filepath.Ext(strings.TrimSuffix(lower, ext))) == ".tar" {
+			return archiveKindTarBz2
+		}
+	}
+	return archiveKindNone
+}
+
+// ArchiveFS is a read-only FileSystem backed by the fully-extracted
+// contents of a .zip, .tar, .tar.gz, or .tar.bz2 archive.
+type ArchiveFS struct {
+	entries map[string][]byte
+}
+
+// OpenArchive detects archivePath's format and extracts it into a
+// read-only FileSystem.
+func OpenArchive(archivePath string) (*ArchiveFS, error) {
+	switch detectArchiveKind(archivePath) {
+	case archiveKindZip:
+		return openZipArchive(archivePath)
+	case archiveKindTar:
+		return openTarArchive(archivePath, func(r io.Reader) (io.Reader, error) { return r, nil })
+	case archiveKindTarGz:
+		return openTarArchive(archivePath, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) })
+	case archiveKindTarBz2:
+		return openTarArchive(archivePath, func(r io.Reader) (io.Reader, error) { return bzip2.NewReader(r), nil })
+	default:
+		return nil, errors.New("unsupported archive format: " + archivePath)
+	}
+}
+
+func openZipArchive(archivePath string) (*ArchiveFS, error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	entries := make(map[string][]byte)
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		entries[archiveEntryPath(f.Name)] = data
+	}
+	return &ArchiveFS{entries: entries}, nil
+}
+
+func openTarArchive(archivePath string, wrap func(io.Reader) (io.Reader, error)) (*ArchiveFS, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := wrap(bufio.NewReader(f))
+	if err != nil {
+		return nil, err
+	}
+
+	tr := tar.NewReader(r)
+	entries := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		entries[archiveEntryPath(hdr.Name)] = data
+	}
+	return &ArchiveFS{entries: entries}, nil
+}
+
+func archiveEntryPath(name string) string {
+	return "/" + strings.TrimPrefix(filepath.ToSlash(name), "/")
+}
+
+func (a *ArchiveFS) Open(name string) (io.ReadCloser, error) {
+	data, ok := a.entries[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (a *ArchiveFS) Create(name string) (io.WriteCloser, error) {
+	return nil, errArchiveReadOnly
+}
+
+func (a *ArchiveFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return errArchiveReadOnly
+}
+
+func (a *ArchiveFS) Stat(name string) (os.FileInfo, error) {
+	if data, ok := a.entries[name]; ok {
+		return &memFileInfo{name: filepath.Base(name), size: int64(len(data))}, nil
+	}
+	if a.hasDir(name) {
+		return &memFileInfo{name: filepath.Base(name), isDir: true}, nil
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+// Walk visits every file stored under root in lexical order. Unlike
+// filepath.Walk, it does not call walkFn for directories.
+func (a *ArchiveFS) Walk(root string, walkFn filepath.WalkFunc) error {
+	root = filepath.Clean(root)
+
+	if _, isFile := a.entries[root]; !isFile && root != "/" && !a.hasDir(root) {
+		return &os.PathError{Op: "walk", Path: root, Err: os.ErrNotExist}
+	}
+
+	var paths []string
+	for p := range a.entries {
+		if root == "/" || p == root || strings.HasPrefix(p, root+"/") {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		info := &memFileInfo{name: filepath.Base(p), size: int64(len(a.entries[p]))}
+		if err := walkFn(p, info, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *ArchiveFS) hasDir(dir string) bool {
+	dir = filepath.Clean(dir)
+	for p := range a.entries {
+		if strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
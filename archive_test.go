@@ -0,0 +1,139 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectArchiveKind(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want archiveKind
+	}{
+		{"zip", "docs.zip", archiveKindZip},
+		{"tar", "docs.tar", archiveKindTar},
+		{"tar.gz", "docs.tar.gz", archiveKindTarGz},
+		{"tar.bz2", "docs.tar.bz2", archiveKindTarBz2},
+		{"uppercase ZIP", "docs.ZIP", archiveKindZip},
+		{"bare gz is not a tarball", "docs.gz", archiveKindNone},
+		{"unrelated extension", "docs.md", archiveKindNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectArchiveKind(tt.path); got != tt.want {
+				t.Errorf("detectArchiveKind(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenArchive_Zip_ExposesMarkdownEntries(t *testing.T) {
+	// Arrange
+	archivePath := filepath.Join(t.TempDir(), "docs.zip")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("notes/readme.md")
+	if err != nil {
+		t.Fatalf("failed to add zip entry: %v", err)
+	}
+	if _, err := w.Write([]byte("# Notes")); err != nil {
+		t.Fatalf("failed to write zip entry: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close archive: %v", err)
+	}
+
+	// Act
+	fsys, err := OpenArchive(archivePath)
+	if err != nil {
+		t.Fatalf("OpenArchive failed: %v", err)
+	}
+
+	// Assert
+	files, err := FindMarkdownFiles(fsys, "/", nil)
+	if err != nil {
+		t.Fatalf("FindMarkdownFiles failed: %v", err)
+	}
+	if len(files) != 1 || files[0] != "/notes/readme.md" {
+		t.Errorf("got %v, want [/notes/readme.md]", files)
+	}
+
+	content, err := LoadFileContent(fsys, "/notes/readme.md")
+	if err != nil {
+		t.Fatalf("LoadFileContent failed: %v", err)
+	}
+	if content != "# Notes" {
+		t.Errorf("got %q, want %q", content, "# Notes")
+	}
+}
+
+func TestOpenArchive_TarGz_ExposesMarkdownEntries(t *testing.T) {
+	// Arrange
+	archivePath := filepath.Join(t.TempDir(), "docs.tar.gz")
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("failed to create archive: %v", err)
+	}
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	contents := []byte("# Guide")
+	if err := tw.WriteHeader(&tar.Header{Name: "guide.md", Size: int64(len(contents)), Mode: 0644}); err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	if _, err := tw.Write(contents); err != nil {
+		t.Fatalf("failed to write tar entry: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("failed to close gzip writer: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close archive: %v", err)
+	}
+
+	// Act
+	fsys, err := OpenArchive(archivePath)
+	if err != nil {
+		t.Fatalf("OpenArchive failed: %v", err)
+	}
+
+	// Assert
+	content, err := LoadFileContent(fsys, "/guide.md")
+	if err != nil {
+		t.Fatalf("LoadFileContent failed: %v", err)
+	}
+	if content != "# Guide" {
+		t.Errorf("got %q, want %q", content, "# Guide")
+	}
+}
+
+func TestArchiveFS_WriteFile_ReturnsReadOnlyError(t *testing.T) {
+	fsys := &ArchiveFS{entries: map[string][]byte{"/a.md": []byte("x")}}
+	if err := fsys.WriteFile("/a.md", []byte("y"), 0644); err != errArchiveReadOnly {
+		t.Errorf("got %v, want %v", err, errArchiveReadOnly)
+	}
+}
+
+func TestArchiveFS_Walk_NonExistentDirectory_ReturnsError(t *testing.T) {
+	fsys := &ArchiveFS{entries: map[string][]byte{"/a.md": []byte("x")}}
+	err := fsys.Walk("/nonexistent", func(path string, info os.FileInfo, err error) error {
+		return nil
+	})
+	if err == nil {
+		t.Error("expected an error for a non-existent directory, got nil")
+	}
+}
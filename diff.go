@@ -0,0 +1,75 @@
+package main
+
+import "strings"
+
+// DiffKind classifies a single line produced by LineDiff.
+type DiffKind int
+
+const (
+	DiffSame DiffKind = iota
+	DiffRemoved
+	DiffAdded
+)
+
+// DiffLine is one line of a LineDiff result.
+type DiffLine struct {
+	Kind DiffKind
+	Text string
+}
+
+// LineDiff computes a simple line-based diff between mine and disk by
+// collapsing their common prefix and suffix, leaving only the differing
+// middle section marked as removed (disk) / added (mine). The middle
+// section is not diffed further, so the result is not minimal, and moved
+// or reordered lines are not detected, which is enough for the conflict
+// view this feeds.
+func LineDiff(mine, disk string) []DiffLine {
+	mineLines := strings.Split(mine, "\n")
+	diskLines := strings.Split(disk, "\n")
+
+	prefix := 0
+	for prefix < len(mineLines) && prefix < len(diskLines) && mineLines[prefix] == diskLines[prefix] {
+		prefix++
+	}
+
+	mineEnd := len(mineLines)
+	diskEnd := len(diskLines)
+	for mineEnd > prefix && diskEnd > prefix && mineLines[mineEnd-1] == diskLines[diskEnd-1] {
+		mineEnd--
+		diskEnd--
+	}
+
+	var lines []DiffLine
+	for _, l := range mineLines[:prefix] {
+		lines = append(lines, DiffLine{Kind: DiffSame, Text: l})
+	}
+	for _, l := range diskLines[prefix:diskEnd] {
+		lines = append(lines, DiffLine{Kind: DiffRemoved, Text: l})
+	}
+	for _, l := range mineLines[prefix:mineEnd] {
+		lines = append(lines, DiffLine{Kind: DiffAdded, Text: l})
+	}
+	for _, l := range mineLines[mineEnd:] {
+		lines = append(lines, DiffLine{Kind: DiffSame, Text: l})
+	}
+	return lines
+}
+
+// FormatDiff renders lines as a plain-text diff, prefixing removed lines
+// (from disk) with "-" and added lines (mine) with "+".
+func FormatDiff(lines []DiffLine) string {
+	var b strings.Builder
+	for _, l := range lines {
+		switch l.Kind {
+		case DiffRemoved:
+			b.WriteString("- ")
+		case DiffAdded:
+			b.WriteString("+ ")
+		default:
+			b.WriteString("  ")
+		}
+		b.WriteString(l.Text)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
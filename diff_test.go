@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestLineDiff_IdenticalText_AllSame(t *testing.T) {
+	lines := LineDiff("a\nb\nc", "a\nb\nc")
+	for _, l := range lines {
+		if l.Kind != DiffSame {
+			t.Errorf("expected all lines same, got %+v", l)
+		}
+	}
+}
+
+func TestLineDiff_ChangedMiddleLine_MarksRemovedAndAdded(t *testing.T) {
+	lines := LineDiff("a\nmine\nc", "a\ndisk\nc")
+
+	var removed, added []string
+	for _, l := range lines {
+		switch l.Kind {
+		case DiffRemoved:
+			removed = append(removed, l.Text)
+		case DiffAdded:
+			added = append(added, l.Text)
+		}
+	}
+
+	if len(removed) != 1 || removed[0] != "disk" {
+		t.Errorf("got removed=%v, want [disk]", removed)
+	}
+	if len(added) != 1 || added[0] != "mine" {
+		t.Errorf("got added=%v, want [mine]", added)
+	}
+}
+
+func TestFormatDiff_PrefixesLinesByKind(t *testing.T) {
+	lines := []DiffLine{
+		{Kind: DiffSame, Text: "same"},
+		{Kind: DiffRemoved, Text: "old"},
+		{Kind: DiffAdded, Text: "new"},
+	}
+
+	got := FormatDiff(lines)
+	want := "  same\n- old\n+ new\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// ExportFormat selects the output produced by Converter.
+type ExportFormat int
+
+const (
+	ExportHTML ExportFormat = iota
+	ExportPDF
+	ExportEPUB
+)
+
+// defaultHTMLTheme is used when Converter.Theme is empty or unrecognized.
+const defaultHTMLTheme = "light"
+
+// htmlThemes are the built-in stylesheets available to HTML and PDF
+// export; each is inlined into the generated document so it stays a
+// single, self-contained file.
+var htmlThemes = map[string]string{
+	"light": `body{font-family:Georgia,serif;max-width:42em;margin:2em auto;padding:0 1em;color:#222;background:#fff}
+code,pre{font-family:Menlo,Consolas,monospace;background:#f4f4f4;padding:.2em .4em;border-radius:3px}
+pre code{padding:0;background:none}
+blockquote{border-left:4px solid #ddd;margin:0;padding-left:1em;color:#555}`,
+	"dark": `body{font-family:Georgia,serif;max-width:42em;margin:2em auto;padding:0 1em;color:#ddd;background:#1e1e1e}
+a{color:#6cb6ff}
+code,pre{font-family:Menlo,Consolas,monospace;background:#2a2a2a;padding:.2em .4em;border-radius:3px}
+pre code{padding:0;background:none}
+blockquote{border-left:4px solid #444;margin:0;padding-left:1em;color:#999}`,
+	"sepia": `body{font-family:Georgia,serif;max-width:42em;margin:2em auto;padding:0 1em;color:#5b4636;background:#f4ecd8}
+code,pre{font-family:Menlo,Consolas,monospace;background:#ece0c4;padding:.2em .4em;border-radius:3px}
+pre code{padding:0;background:none}
+blockquote{border-left:4px solid #d3c4a0;margin:0;padding-left:1em;color:#7a6a57}`,
+}
+
+// errWkhtmltopdfMissing is returned by Converter.Convert when PDF export is
+// requested but the wkhtmltopdf binary isn't on PATH, so callers can show a
+// targeted error dialog instead of a generic failure.
+var errWkhtmltopdfMissing = errors.New("wkhtmltopdf not found in PATH")
+
+// Converter renders one or more markdown documents read via a FileSystem
+// into a single exported file on disk. It has no dependency on the UI so
+// it can be exercised directly in tests.
+type Converter struct {
+	// BasePath is the source document (HTML/PDF) or source directory
+	// (EPUB, when Files is empty) to export.
+	BasePath string
+	// OutputPath is where the exported file is written, always on the
+	// local filesystem regardless of which FileSystem BasePath is read
+	// from.
+	OutputPath string
+	// Format selects which exporter runs.
+	Format ExportFormat
+	// Files lists the markdown documents making up an EPUB export, in
+	// reading order. If empty, BasePath is walked for markdown files.
+	Files []string
+	// Theme names a built-in stylesheet inlined into HTML/PDF output.
+	// Defaults to defaultHTMLTheme when empty or unrecognized.
+	Theme string
+}
+
+// Convert reads the source document(s) via fsys and writes the exported
+// file to c.OutputPath.
+func (c *Converter) Convert(fsys FileSystem) error {
+	switch c.Format {
+	case ExportHTML:
+		return c.convertHTML(fsys)
+	case ExportPDF:
+		return c.convertPDF(fsys)
+	case ExportEPUB:
+		return c.convertEPUB(fsys)
+	default:
+		return fmt.Errorf("unsupported export format: %v", c.Format)
+	}
+}
+
+func (c *Converter) themeCSS() string {
+	if css, ok := htmlThemes[c.Theme]; ok {
+		return css
+	}
+	return htmlThemes[defaultHTMLTheme]
+}
+
+// renderMarkdownHTML converts markdown to an HTML fragment via goldmark.
+func renderMarkdownHTML(markdown string) (string, error) {
+	var buf strings.Builder
+	if err := goldmark.Convert([]byte(markdown), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// xhtmlMarkdown renders void elements (<br/>, <hr/>, <img/>) self-closed,
+// which EPUB chapters require since readers parse them as strict XML.
+var xhtmlMarkdown = goldmark.New(goldmark.WithRendererOptions(html.WithXHTML()))
+
+// renderMarkdownXHTML converts markdown to an XHTML fragment suitable for
+// embedding in an EPUB chapter.
+func renderMarkdownXHTML(markdown string) (string, error) {
+	var buf strings.Builder
+	if err := xhtmlMarkdown.Convert([]byte(markdown), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// wrapHTMLDocument embeds body and css into a standalone HTML document.
+func wrapHTMLDocument(title, body, css string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+%s
+</style>
+</head>
+<body>
+%s
+</body>
+</html>
+`, escapeXML(title), css, body)
+}
+
+func (c *Converter) convertHTML(fsys FileSystem) error {
+	markdown, err := LoadFileContent(fsys, c.BasePath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", c.BasePath, err)
+	}
+	body, err := renderMarkdownHTML(markdown)
+	if err != nil {
+		return fmt.Errorf("rendering %s: %w", c.BasePath, err)
+	}
+	doc := wrapHTMLDocument(filepath.Base(c.BasePath), body, c.themeCSS())
+	return os.WriteFile(c.OutputPath, []byte(doc), 0644)
+}
+
+// convertPDF renders the document to HTML, then shells out to wkhtmltopdf
+// to produce the PDF. It returns errWkhtmltopdfMissing if the binary isn't
+// installed, so the caller can show a graceful error rather than a raw
+// exec failure.
+func (c *Converter) convertPDF(fsys FileSystem) error {
+	bin, err := exec.LookPath("wkhtmltopdf")
+	if err != nil {
+		return errWkhtmltopdfMissing
+	}
+
+	markdown, err := LoadFileContent(fsys, c.BasePath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", c.BasePath, err)
+	}
+	body, err := renderMarkdownHTML(markdown)
+	if err != nil {
+		return fmt.Errorf("rendering %s: %w", c.BasePath, err)
+	}
+	doc := wrapHTMLDocument(filepath.Base(c.BasePath), body, c.themeCSS())
+
+	tmp, err := os.CreateTemp("", "markdown-export-*.html")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(doc); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command(bin, tmp.Name(), c.OutputPath)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("wkhtmltopdf: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// epubChapter is one markdown document converted to an XHTML chapter.
+type epubChapter struct {
+	ID       string
+	Title    string
+	FileName string
+	Body     string
+}
+
+func (c *Converter) convertEPUB(fsys FileSystem) error {
+	files := c.Files
+	if len(files) == 0 {
+		found, err := FindMarkdownFiles(fsys, c.BasePath, nil)
+		if err != nil {
+			return fmt.Errorf("walking %s: %w", c.BasePath, err)
+		}
+		sort.Strings(found)
+		files = found
+	}
+	if len(files) == 0 {
+		return errors.New("no markdown documents to export")
+	}
+
+	chapters := make([]epubChapter, 0, len(files))
+	for i, f := range files {
+		markdown, err := LoadFileContent(fsys, f)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", f, err)
+		}
+		body, err := renderMarkdownXHTML(markdown)
+		if err != nil {
+			return fmt.Errorf("rendering %s: %w", f, err)
+		}
+		id := fmt.Sprintf("chapter%d", i+1)
+		chapters = append(chapters, epubChapter{
+			ID:       id,
+			Title:    strings.TrimSuffix(filepath.Base(f), filepath.Ext(f)),
+			FileName: id + ".xhtml",
+			Body:     body,
+		})
+	}
+
+	title := epubTitle(c.BasePath)
+
+	out, err := os.Create(c.OutputPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	zw := zip.NewWriter(out)
+
+	// mimetype must be the first entry and stored uncompressed for
+	// readers that sniff the archive before parsing it as zip.
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := mw.Write([]byte("application/epub+zip")); err != nil {
+		return err
+	}
+
+	if err := writeZipFile(zw, "META-INF/container.xml", epubContainerXML); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "OEBPS/content.opf", buildContentOPF(title, chapters)); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "OEBPS/toc.ncx", buildTocNCX(title, chapters)); err != nil {
+		return err
+	}
+	for _, ch := range chapters {
+		if err := writeZipFile(zw, "OEBPS/"+ch.FileName, wrapXHTMLChapter(ch.Title, ch.Body)); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func writeZipFile(zw *zip.Writer, name, content string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write([]byte(content))
+	return err
+}
+
+// epubTitle derives a book title from a source path, falling back to a
+// generic title for the root of a filesystem.
+func epubTitle(basePath string) string {
+	base := filepath.Base(filepath.Clean(basePath))
+	if base == "" || base == "." || base == string(filepath.Separator) {
+		return "Markdown Collection"
+	}
+	return base
+}
+
+const epubContainerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+// buildContentOPF renders the package document (OPF) listing metadata, the
+// manifest of chapter files, and the linear reading order.
+func buildContentOPF(title string, chapters []epubChapter) string {
+	var manifest, spine strings.Builder
+	for _, ch := range chapters {
+		fmt.Fprintf(&manifest, `    <item id="%s" href="%s" media-type="application/xhtml+xml"/>`+"\n", ch.ID, ch.FileName)
+		fmt.Fprintf(&spine, `    <itemref idref="%s"/>`+"\n", ch.ID)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0" unique-identifier="BookId">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:title>%s</dc:title>
+    <dc:language>en</dc:language>
+    <dc:identifier id="BookId">urn:uuid:%s</dc:identifier>
+  </metadata>
+  <manifest>
+    <item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+%s  </manifest>
+  <spine toc="ncx">
+%s  </spine>
+</package>
+`, escapeXML(title), epubIdentifier(title), manifest.String(), spine.String())
+}
+
+// buildTocNCX renders the navigation control file (NCX) that lists
+// chapters in the reader's table of contents.
+func buildTocNCX(title string, chapters []epubChapter) string {
+	var navPoints strings.Builder
+	for i, ch := range chapters {
+		fmt.Fprintf(&navPoints, `    <navPoint id="%s" playOrder="%d">
+      <navLabel><text>%s</text></navLabel>
+      <content src="%s"/>
+    </navPoint>
+`, ch.ID, i+1, escapeXML(ch.Title), ch.FileName)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+  <head>
+    <meta name="dtb:uid" content="urn:uuid:%s"/>
+  </head>
+  <docTitle><text>%s</text></docTitle>
+  <navMap>
+%s  </navMap>
+</ncx>
+`, epubIdentifier(title), escapeXML(title), navPoints.String())
+}
+
+func wrapXHTMLChapter(title, body string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><title>%s</title></head>
+<body>
+%s
+</body>
+</html>
+`, escapeXML(title), body)
+}
+
+// epubIdentifier derives a stable, deterministic identifier for title so
+// re-exporting the same collection doesn't churn the OPF/NCX identifiers.
+func epubIdentifier(title string) string {
+	return strings.NewReplacer(" ", "-", "/", "-").Replace(strings.ToLower(title))
+}
+
+var xmlEscaper = strings.NewReplacer(
+	`&`, "&amp;",
+	`<`, "&lt;",
+	`>`, "&gt;",
+	`"`, "&quot;",
+	`'`, "&apos;",
+)
+
+func escapeXML(s string) string {
+	return xmlEscaper.Replace(s)
+}
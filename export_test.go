@@ -0,0 +1,204 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConverter_HTML_WritesSelfContainedDocument(t *testing.T) {
+	// Arrange
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("/docs/guide.md", []byte("# Guide\n\nSome *text*."), 0644); err != nil {
+		t.Fatalf("failed to seed file: %v", err)
+	}
+	outPath := filepath.Join(t.TempDir(), "guide.html")
+	conv := &Converter{BasePath: "/docs/guide.md", OutputPath: outPath, Format: ExportHTML}
+
+	// Act
+	if err := conv.Convert(fsys); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	// Assert
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	got := string(data)
+	if !strings.Contains(got, "<style>") {
+		t.Errorf("expected embedded stylesheet, got %q", got)
+	}
+	if !strings.Contains(got, "<h1>Guide</h1>") {
+		t.Errorf("expected rendered heading, got %q", got)
+	}
+	if !strings.Contains(got, "<em>text</em>") {
+		t.Errorf("expected rendered emphasis, got %q", got)
+	}
+}
+
+func TestConverter_HTML_UnknownTheme_FallsBackToDefault(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("/a.md", []byte("body"), 0644); err != nil {
+		t.Fatalf("failed to seed file: %v", err)
+	}
+	outPath := filepath.Join(t.TempDir(), "a.html")
+	conv := &Converter{BasePath: "/a.md", OutputPath: outPath, Format: ExportHTML, Theme: "nonexistent"}
+
+	if err := conv.Convert(fsys); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read output: %v", err)
+	}
+	if !strings.Contains(string(data), htmlThemes[defaultHTMLTheme]) {
+		t.Error("expected output to fall back to the default theme's CSS")
+	}
+}
+
+func TestConverter_PDF_WithoutWkhtmltopdf_ReturnsSentinelError(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("/a.md", []byte("body"), 0644); err != nil {
+		t.Fatalf("failed to seed file: %v", err)
+	}
+	conv := &Converter{BasePath: "/a.md", OutputPath: filepath.Join(t.TempDir(), "a.pdf"), Format: ExportPDF}
+
+	if err := conv.Convert(fsys); err != errWkhtmltopdfMissing {
+		t.Errorf("got %v, want %v", err, errWkhtmltopdfMissing)
+	}
+}
+
+func TestConverter_EPUB_ProducesValidZipWithMimetypeFirst(t *testing.T) {
+	// Arrange
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("/book/ch1.md", []byte("# One"), 0644); err != nil {
+		t.Fatalf("failed to seed file: %v", err)
+	}
+	if err := fsys.WriteFile("/book/ch2.md", []byte("# Two"), 0644); err != nil {
+		t.Fatalf("failed to seed file: %v", err)
+	}
+	outPath := filepath.Join(t.TempDir(), "book.epub")
+	conv := &Converter{
+		BasePath:   "/book",
+		OutputPath: outPath,
+		Format:     ExportEPUB,
+		Files:      []string{"/book/ch1.md", "/book/ch2.md"},
+	}
+
+	// Act
+	if err := conv.Convert(fsys); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	// Assert
+	r, err := zip.OpenReader(outPath)
+	if err != nil {
+		t.Fatalf("failed to open epub as zip: %v", err)
+	}
+	defer r.Close()
+
+	if len(r.File) == 0 || r.File[0].Name != "mimetype" {
+		t.Fatalf("expected mimetype to be the first zip entry, got %v", r.File)
+	}
+	if r.File[0].Method != zip.Store {
+		t.Errorf("expected mimetype to be stored uncompressed, got method %v", r.File[0].Method)
+	}
+
+	names := make(map[string]bool)
+	for _, f := range r.File {
+		names[f.Name] = true
+	}
+	for _, want := range []string{"META-INF/container.xml", "OEBPS/content.opf", "OEBPS/toc.ncx", "OEBPS/chapter1.xhtml", "OEBPS/chapter2.xhtml"} {
+		if !names[want] {
+			t.Errorf("expected epub to contain %s, got %v", want, names)
+		}
+	}
+}
+
+func TestConverter_EPUB_NoMarkdownFiles_ReturnsError(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.Mkdir("/empty"); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	conv := &Converter{BasePath: "/empty", OutputPath: filepath.Join(t.TempDir(), "empty.epub"), Format: ExportEPUB}
+
+	if err := conv.Convert(fsys); err == nil {
+		t.Error("expected error for a collection with no markdown files, got nil")
+	}
+}
+
+func TestRenderMarkdownHTML_RendersBasicCommonMark(t *testing.T) {
+	got, err := renderMarkdownHTML("# Title\n\n- one\n- two")
+	if err != nil {
+		t.Fatalf("renderMarkdownHTML failed: %v", err)
+	}
+	for _, want := range []string{"<h1>Title</h1>", "<li>one</li>", "<li>two</li>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %q, want substring %q", got, want)
+		}
+	}
+}
+
+func TestRenderMarkdownXHTML_SelfClosesVoidElements(t *testing.T) {
+	got, err := renderMarkdownXHTML("line one  \nline two\n\n---\n\n![alt](pic.png)")
+	if err != nil {
+		t.Fatalf("renderMarkdownXHTML failed: %v", err)
+	}
+	for _, want := range []string{"<br />", "<hr />", `<img src="pic.png" alt="alt" />`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %q, want substring %q", got, want)
+		}
+	}
+}
+
+func TestEscapeXML_EscapesReservedCharacters(t *testing.T) {
+	got := escapeXML(`<a> & "b" 'c'`)
+	want := "&lt;a&gt; &amp; &quot;b&quot; &apos;c&apos;"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestConverter_EPUB_DerivesTitleFromBasePath(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("/notes/a.md", []byte("# A"), 0644); err != nil {
+		t.Fatalf("failed to seed file: %v", err)
+	}
+	outPath := filepath.Join(t.TempDir(), "notes.epub")
+	conv := &Converter{BasePath: "/notes", OutputPath: outPath, Format: ExportEPUB, Files: []string{"/notes/a.md"}}
+
+	if err := conv.Convert(fsys); err != nil {
+		t.Fatalf("Convert failed: %v", err)
+	}
+
+	r, err := zip.OpenReader(outPath)
+	if err != nil {
+		t.Fatalf("failed to open epub: %v", err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name != "OEBPS/content.opf" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open content.opf: %v", err)
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(rc); err != nil {
+			t.Fatalf("failed to read content.opf: %v", err)
+		}
+		rc.Close()
+		if !strings.Contains(buf.String(), "<dc:title>notes</dc:title>") {
+			t.Errorf("expected title derived from base path, got %q", buf.String())
+		}
+	}
+}
@@ -1,40 +1,84 @@
 package main
 
 import (
+	"io"
 	"os"
-	"path/filepath"
 	"strings"
 )
 
-// LoadFileContent reads and returns the content of a file
-func LoadFileContent(filePath string) (string, error) {
-	content, err := os.ReadFile(filePath)
+// DefaultMarkdownExtensions are the extensions treated as markdown when the
+// user hasn't configured a custom set.
+var DefaultMarkdownExtensions = []string{".md", ".markdown", ".mdown", ".mkd", ".txt"}
+
+// LoadFileContent reads and returns the content of a file via fsys
+func LoadFileContent(fsys FileSystem, filePath string) (string, error) {
+	f, err := fsys.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
 	if err != nil {
 		return "", err
 	}
 	return string(content), nil
 }
 
-// SaveFileContent writes content to a file
-func SaveFileContent(filePath string, content string) error {
-	return os.WriteFile(filePath, []byte(content), 0644)
+// SaveFileContent writes content to a file via fsys
+func SaveFileContent(fsys FileSystem, filePath string, content string) error {
+	return fsys.WriteFile(filePath, []byte(content), 0644)
 }
 
-// IsMarkdownFile checks if a filename has a markdown extension
-func IsMarkdownFile(filename string) bool {
+// IsMarkdownFile checks if filename ends with one of extensions
+// (case-insensitive). If extensions is empty, DefaultMarkdownExtensions is
+// used instead.
+func IsMarkdownFile(filename string, extensions []string) bool {
+	if len(extensions) == 0 {
+		extensions = DefaultMarkdownExtensions
+	}
+
 	lower := strings.ToLower(filename)
-	return strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".markdown")
+	for _, ext := range extensions {
+		if strings.HasSuffix(lower, strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseExtensionList splits a comma-separated list of extensions (as typed
+// by a user) into a normalized slice, each entry lowercased and prefixed
+// with a leading dot. Falls back to DefaultMarkdownExtensions when text
+// contains no usable entries.
+func ParseExtensionList(text string) []string {
+	var extensions []string
+	for _, part := range strings.Split(text, ",") {
+		ext := strings.ToLower(strings.TrimSpace(part))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		extensions = append(extensions, ext)
+	}
+	if len(extensions) == 0 {
+		return append([]string(nil), DefaultMarkdownExtensions...)
+	}
+	return extensions
 }
 
-// FindMarkdownFiles recursively finds all markdown files in a directory
-func FindMarkdownFiles(dirPath string) ([]string, error) {
+// FindMarkdownFiles recursively finds all files matching extensions in a
+// directory via fsys
+func FindMarkdownFiles(fsys FileSystem, dirPath string, extensions []string) ([]string, error) {
 	var files []string
 
-	err := filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+	err := fsys.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if !info.IsDir() && IsMarkdownFile(path) {
+		if !info.IsDir() && IsMarkdownFile(path, extensions) {
 			files = append(files, path)
 		}
 		return nil
@@ -43,8 +87,8 @@ func FindMarkdownFiles(dirPath string) ([]string, error) {
 	return files, err
 }
 
-// CreateMarkdownFile creates a new markdown file with default content
-func CreateMarkdownFile(filePath string) error {
+// CreateMarkdownFile creates a new markdown file with default content via fsys
+func CreateMarkdownFile(fsys FileSystem, filePath string) error {
 	defaultContent := "# New Document\n\nStart writing..."
-	return SaveFileContent(filePath, defaultContent)
+	return SaveFileContent(fsys, filePath, defaultContent)
 }
@@ -1,23 +1,21 @@
 package main
 
 import (
-	"os"
 	"path/filepath"
 	"testing"
 )
 
 func TestLoadFileContent_ValidFile_ReturnsContent(t *testing.T) {
 	// Arrange
-	tmpDir := t.TempDir()
-	testFile := filepath.Join(tmpDir, "test.md")
+	fsys := NewMemFS()
+	testFile := "/workspace/test.md"
 	expectedContent := "# Test\n\nContent here"
-	err := os.WriteFile(testFile, []byte(expectedContent), 0644)
-	if err != nil {
+	if err := fsys.WriteFile(testFile, []byte(expectedContent), 0644); err != nil {
 		t.Fatalf("failed to create test file: %v", err)
 	}
 
 	// Act
-	content, err := LoadFileContent(testFile)
+	content, err := LoadFileContent(fsys, testFile)
 
 	// Assert
 	if err != nil {
@@ -30,7 +28,7 @@ func TestLoadFileContent_ValidFile_ReturnsContent(t *testing.T) {
 
 func TestLoadFileContent_NonExistentFile_ReturnsError(t *testing.T) {
 	// Act
-	_, err := LoadFileContent("/nonexistent/file.md")
+	_, err := LoadFileContent(NewMemFS(), "/nonexistent/file.md")
 
 	// Assert
 	if err == nil {
@@ -40,15 +38,14 @@ func TestLoadFileContent_NonExistentFile_ReturnsError(t *testing.T) {
 
 func TestLoadFileContent_EmptyFile_ReturnsEmptyString(t *testing.T) {
 	// Arrange
-	tmpDir := t.TempDir()
-	testFile := filepath.Join(tmpDir, "empty.md")
-	err := os.WriteFile(testFile, []byte(""), 0644)
-	if err != nil {
+	fsys := NewMemFS()
+	testFile := "/workspace/empty.md"
+	if err := fsys.WriteFile(testFile, []byte(""), 0644); err != nil {
 		t.Fatalf("failed to create test file: %v", err)
 	}
 
 	// Act
-	content, err := LoadFileContent(testFile)
+	content, err := LoadFileContent(fsys, testFile)
 
 	// Assert
 	if err != nil {
@@ -61,12 +58,12 @@ func TestLoadFileContent_EmptyFile_ReturnsEmptyString(t *testing.T) {
 
 func TestSaveFileContent_ValidPath_CreatesFile(t *testing.T) {
 	// Arrange
-	tmpDir := t.TempDir()
-	testFile := filepath.Join(tmpDir, "save-test.md")
+	fsys := NewMemFS()
+	testFile := "/workspace/save-test.md"
 	content := "# Saved Content\n\nThis was saved"
 
 	// Act
-	err := SaveFileContent(testFile, content)
+	err := SaveFileContent(fsys, testFile, content)
 
 	// Assert
 	if err != nil {
@@ -74,18 +71,24 @@ func TestSaveFileContent_ValidPath_CreatesFile(t *testing.T) {
 	}
 
 	// Verify file was created with correct content
-	savedContent, err := os.ReadFile(testFile)
+	savedContent, err := LoadFileContent(fsys, testFile)
 	if err != nil {
 		t.Errorf("failed to read saved file: %v", err)
 	}
-	if string(savedContent) != content {
-		t.Errorf("got %q, want %q", string(savedContent), content)
+	if savedContent != content {
+		t.Errorf("got %q, want %q", savedContent, content)
 	}
 }
 
 func TestSaveFileContent_InvalidPath_ReturnsError(t *testing.T) {
+	// Arrange
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("/invalid", []byte("placeholder"), 0644); err != nil {
+		t.Fatalf("failed to seed conflicting path: %v", err)
+	}
+
 	// Act
-	err := SaveFileContent("/invalid/nonexistent/path/file.md", "content")
+	err := SaveFileContent(fsys, "/invalid/nonexistent/path/file.md", "content")
 
 	// Assert
 	if err == nil {
@@ -93,7 +96,7 @@ func TestSaveFileContent_InvalidPath_ReturnsError(t *testing.T) {
 	}
 }
 
-func TestIsMarkdownFile(t *testing.T) {
+func TestIsMarkdownFile_DefaultExtensions(t *testing.T) {
 	tests := []struct {
 		name     string
 		filename string
@@ -104,19 +107,57 @@ func TestIsMarkdownFile(t *testing.T) {
 		{"mixed case Md", "test.Md", true},
 		{"markdown extension", "test.markdown", true},
 		{"uppercase MARKDOWN", "test.MARKDOWN", true},
-		{"txt file", "test.txt", false},
+		{"txt file", "test.txt", true},
 		{"no extension", "test", false},
 		{"empty string", "", false},
-		{"md in middle", "test.md.txt", false},
+		{"md in middle ending in txt", "test.md.txt", true},
 		{"just md", ".md", true},
 		{"path with md", "/path/to/file.md", true},
+		{"unconfigured extension", "test.rst", false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := IsMarkdownFile(tt.filename)
+			got := IsMarkdownFile(tt.filename, nil)
 			if got != tt.want {
-				t.Errorf("IsMarkdownFile(%q) = %v, want %v", tt.filename, got, tt.want)
+				t.Errorf("IsMarkdownFile(%q, nil) = %v, want %v", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsMarkdownFile_CustomExtensions(t *testing.T) {
+	extensions := []string{".rst"}
+
+	if !IsMarkdownFile("guide.rst", extensions) {
+		t.Error("expected guide.rst to match custom extension list")
+	}
+	if IsMarkdownFile("guide.md", extensions) {
+		t.Error("expected guide.md to be excluded when not in custom extension list")
+	}
+}
+
+func TestParseExtensionList(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"comma separated without dots", "md, markdown, txt", []string{".md", ".markdown", ".txt"}},
+		{"already dotted", ".md,.rst", []string{".md", ".rst"}},
+		{"blank falls back to defaults", "  ", DefaultMarkdownExtensions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseExtensionList(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
 			}
 		})
 	}
@@ -124,10 +165,13 @@ func TestIsMarkdownFile(t *testing.T) {
 
 func TestFindMarkdownFiles_EmptyDirectory_ReturnsEmptySlice(t *testing.T) {
 	// Arrange
-	tmpDir := t.TempDir()
+	fsys := NewMemFS()
+	if err := fsys.Mkdir("/workspace"); err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
 
 	// Act
-	files, err := FindMarkdownFiles(tmpDir)
+	files, err := FindMarkdownFiles(fsys, "/workspace", nil)
 
 	// Assert
 	if err != nil {
@@ -140,20 +184,17 @@ func TestFindMarkdownFiles_EmptyDirectory_ReturnsEmptySlice(t *testing.T) {
 
 func TestFindMarkdownFiles_WithMarkdownFiles_ReturnsAll(t *testing.T) {
 	// Arrange
-	tmpDir := t.TempDir()
-
-	// Create test files
+	fsys := NewMemFS()
 	testFiles := []string{"test1.md", "test2.MD", "test3.markdown", "test4.txt"}
 	for _, filename := range testFiles {
-		filePath := filepath.Join(tmpDir, filename)
-		err := os.WriteFile(filePath, []byte("content"), 0644)
-		if err != nil {
+		filePath := filepath.Join("/workspace", filename)
+		if err := fsys.WriteFile(filePath, []byte("content"), 0644); err != nil {
 			t.Fatalf("failed to create test file %s: %v", filename, err)
 		}
 	}
 
 	// Act
-	files, err := FindMarkdownFiles(tmpDir)
+	files, err := FindMarkdownFiles(fsys, "/workspace", []string{".md", ".markdown"})
 
 	// Assert
 	if err != nil {
@@ -168,28 +209,19 @@ func TestFindMarkdownFiles_WithMarkdownFiles_ReturnsAll(t *testing.T) {
 
 func TestFindMarkdownFiles_NestedDirectories_ReturnsAllRecursively(t *testing.T) {
 	// Arrange
-	tmpDir := t.TempDir()
-	subDir := filepath.Join(tmpDir, "subdir")
-	err := os.Mkdir(subDir, 0755)
-	if err != nil {
-		t.Fatalf("failed to create subdirectory: %v", err)
-	}
+	fsys := NewMemFS()
+	rootFile := "/workspace/root.md"
+	subFile := "/workspace/subdir/sub.md"
 
-	// Create files in root and subdirectory
-	rootFile := filepath.Join(tmpDir, "root.md")
-	subFile := filepath.Join(subDir, "sub.md")
-
-	err = os.WriteFile(rootFile, []byte("root content"), 0644)
-	if err != nil {
+	if err := fsys.WriteFile(rootFile, []byte("root content"), 0644); err != nil {
 		t.Fatalf("failed to create root file: %v", err)
 	}
-	err = os.WriteFile(subFile, []byte("sub content"), 0644)
-	if err != nil {
+	if err := fsys.WriteFile(subFile, []byte("sub content"), 0644); err != nil {
 		t.Fatalf("failed to create sub file: %v", err)
 	}
 
 	// Act
-	files, err := FindMarkdownFiles(tmpDir)
+	files, err := FindMarkdownFiles(fsys, "/workspace", []string{".md", ".markdown"})
 
 	// Assert
 	if err != nil {
@@ -202,21 +234,24 @@ func TestFindMarkdownFiles_NestedDirectories_ReturnsAllRecursively(t *testing.T)
 
 func TestFindMarkdownFiles_NonExistentDirectory_ReturnsError(t *testing.T) {
 	// Act
-	_, err := FindMarkdownFiles("/nonexistent/directory/path")
+	files, err := FindMarkdownFiles(NewMemFS(), "/nonexistent/directory/path", nil)
 
 	// Assert
 	if err == nil {
-		t.Error("expected error for nonexistent directory, got nil")
+		t.Error("expected an error for a non-existent directory, got nil")
+	}
+	if len(files) != 0 {
+		t.Errorf("expected 0 files, got %d", len(files))
 	}
 }
 
 func TestCreateMarkdownFile_ValidPath_CreatesFileWithDefaultContent(t *testing.T) {
 	// Arrange
-	tmpDir := t.TempDir()
-	testFile := filepath.Join(tmpDir, "new.md")
+	fsys := NewMemFS()
+	testFile := "/workspace/new.md"
 
 	// Act
-	err := CreateMarkdownFile(testFile)
+	err := CreateMarkdownFile(fsys, testFile)
 
 	// Assert
 	if err != nil {
@@ -224,20 +259,26 @@ func TestCreateMarkdownFile_ValidPath_CreatesFileWithDefaultContent(t *testing.T
 	}
 
 	// Verify file exists and has default content
-	content, err := os.ReadFile(testFile)
+	content, err := LoadFileContent(fsys, testFile)
 	if err != nil {
 		t.Errorf("failed to read created file: %v", err)
 	}
 
 	expectedContent := "# New Document\n\nStart writing..."
-	if string(content) != expectedContent {
-		t.Errorf("got %q, want %q", string(content), expectedContent)
+	if content != expectedContent {
+		t.Errorf("got %q, want %q", content, expectedContent)
 	}
 }
 
 func TestCreateMarkdownFile_InvalidPath_ReturnsError(t *testing.T) {
+	// Arrange
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("/invalid", []byte("placeholder"), 0644); err != nil {
+		t.Fatalf("failed to seed conflicting path: %v", err)
+	}
+
 	// Act
-	err := CreateMarkdownFile("/invalid/nonexistent/path/file.md")
+	err := CreateMarkdownFile(fsys, "/invalid/nonexistent/path/file.md")
 
 	// Assert
 	if err == nil {
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileSystem abstracts the file operations MarkdownEditor needs so that it
+// can be pointed at local disk, in-memory, archive, or remote-backed
+// sources without changing any of the editor logic.
+type FileSystem interface {
+	// Open opens the named file for reading.
+	Open(name string) (io.ReadCloser, error)
+	// Create creates or truncates the named file for writing.
+	Create(name string) (io.WriteCloser, error)
+	// WriteFile writes data to the named file, creating it if necessary.
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	// Stat returns file info describing the named file.
+	Stat(name string) (os.FileInfo, error)
+	// Walk walks the file tree rooted at root, calling walkFn for each
+	// file or directory, in the same manner as filepath.Walk.
+	Walk(root string, walkFn filepath.WalkFunc) error
+}
+
+// OSFS is a FileSystem backed by the local operating system, preserving
+// the editor's original behavior.
+type OSFS struct{}
+
+func (OSFS) Open(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+func (OSFS) Create(name string) (io.WriteCloser, error) {
+	return os.Create(name)
+}
+
+func (OSFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (OSFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (OSFS) Walk(root string, walkFn filepath.WalkFunc) error {
+	return filepath.Walk(root, walkFn)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ipfsScheme prefixes paths that name content-addressed documents rather
+// than a location on a FileSystem's own namespace.
+const ipfsScheme = "ipfs://"
+
+// DefaultIPFSGateway is the public HTTP gateway used to fetch blobs when no
+// gateway is configured.
+const DefaultIPFSGateway = "https://ipfs.io/ipfs/"
+
+// DefaultIPFSNodeAPI is the local Kubo node API used to pin, unpin, and list
+// content saved through an ipfs:// URI.
+const DefaultIPFSNodeAPI = "http://127.0.0.1:5001"
+
+// dagDirectory is the Links[].Type value the node API uses to mark a
+// directory entry returned by /api/v0/ls.
+const dagDirectory = 1
+
+// IsIPFSPath reports whether path names a content-addressed document.
+func IsIPFSPath(path string) bool {
+	return strings.HasPrefix(path, ipfsScheme)
+}
+
+// httpDoer is satisfied by *http.Client; tests substitute a stub so the
+// gateway and node API are never actually contacted.
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// IPFSFS is a FileSystem backed by content-addressed blobs: reads are
+// fetched through an HTTP gateway, and writes pin to a local node and
+// report back the CID the content was stored under.
+type IPFSFS struct {
+	// Gateway is the base URL used to fetch blobs by path, e.g.
+	// "https://ipfs.io/ipfs/". Defaults to DefaultIPFSGateway.
+	Gateway string
+	// NodeAPI is the base URL of a local node's HTTP API, used to pin,
+	// unpin, and list content. Defaults to DefaultIPFSNodeAPI.
+	NodeAPI string
+	// Client performs the HTTP requests; defaults to http.DefaultClient.
+	Client httpDoer
+}
+
+// NewIPFSFS returns an IPFSFS pointed at the public gateway and a local
+// node's default API address.
+func NewIPFSFS() *IPFSFS {
+	return &IPFSFS{Gateway: DefaultIPFSGateway, NodeAPI: DefaultIPFSNodeAPI, Client: http.DefaultClient}
+}
+
+func (fsys *IPFSFS) gateway() string {
+	if fsys.Gateway != "" {
+		return strings.TrimSuffix(fsys.Gateway, "/")
+	}
+	return strings.TrimSuffix(DefaultIPFSGateway, "/")
+}
+
+func (fsys *IPFSFS) nodeAPI() string {
+	if fsys.NodeAPI != "" {
+		return strings.TrimSuffix(fsys.NodeAPI, "/")
+	}
+	return strings.TrimSuffix(DefaultIPFSNodeAPI, "/")
+}
+
+func (fsys *IPFSFS) client() httpDoer {
+	if fsys.Client != nil {
+		return fsys.Client
+	}
+	return http.DefaultClient
+}
+
+// splitIPFSPath strips the ipfs:// scheme, returning the CID (optionally
+// followed by "/sub/path") that names the document within the DAG.
+func splitIPFSPath(name string) (string, error) {
+	if !IsIPFSPath(name) {
+		return "", fmt.Errorf("not an ipfs:// path: %s", name)
+	}
+	return strings.TrimPrefix(name, ipfsScheme), nil
+}
+
+// Open fetches name's content through the configured gateway.
+func (fsys *IPFSFS) Open(name string) (io.ReadCloser, error) {
+	cidPath, err := splitIPFSPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fsys.gateway()+"/"+cidPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := fsys.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &os.PathError{Op: "open", Path: name, Err: fmt.Errorf("gateway returned %s", resp.Status)}
+	}
+	return resp.Body, nil
+}
+
+// Create is unsupported: content-addressed storage has no name to create
+// ahead of writing. Use WriteFile, or Pin directly to learn the CID.
+func (fsys *IPFSFS) Create(name string) (io.WriteCloser, error) {
+	return nil, errors.New("ipfs: Create is not supported, use WriteFile")
+}
+
+// WriteFile pins data to the configured node. The resulting CID - not
+// name - is what identifies the content; callers that need it should call
+// Pin directly instead.
+func (fsys *IPFSFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	_, err := fsys.Pin(data)
+	return err
+}
+
+// Stat is unsupported: the node API's ls/object endpoints describe DAG
+// links, not arbitrary paths, so there is no cheap way to satisfy it.
+func (fsys *IPFSFS) Stat(name string) (os.FileInfo, error) {
+	return nil, errors.New("ipfs: Stat is not supported")
+}
+
+// Pin adds data to the configured local node, returning the CID it was
+// stored under.
+func (fsys *IPFSFS) Pin(data []byte) (string, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile("file", "blob")
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fsys.nodeAPI()+"/api/v0/add", &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := fsys.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ipfs: node API returned %s", resp.Status)
+	}
+
+	var added struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&added); err != nil {
+		return "", fmt.Errorf("ipfs: decoding add response: %w", err)
+	}
+	if added.Hash == "" {
+		return "", errors.New("ipfs: add response had no Hash")
+	}
+	return added.Hash, nil
+}
+
+// Unpin removes the pin for cid from the configured node, allowing garbage
+// collection to reclaim it.
+func (fsys *IPFSFS) Unpin(cid string) error {
+	req, err := http.NewRequest(http.MethodPost, fsys.nodeAPI()+"/api/v0/pin/rm?arg="+url.QueryEscape(cid), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := fsys.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ipfs: node API returned %s", resp.Status)
+	}
+	return nil
+}
+
+// dagLink is one entry returned by the node's /api/v0/ls for a directory.
+type dagLink struct {
+	Name string `json:"Name"`
+	Type int    `json:"Type"`
+}
+
+// Walk lists root's children via the node API's ls endpoint, recursing
+// into subdirectories, and calls walkFn for each file found, markdown or
+// not -
+// matching filepath.Walk's contract closely enough for FindMarkdownFiles.
+func (fsys *IPFSFS) Walk(root string, walkFn filepath.WalkFunc) error {
+	cid, err := splitIPFSPath(root)
+	if err != nil {
+		return err
+	}
+	return fsys.walkDAG(root, cid, walkFn)
+}
+
+func (fsys *IPFSFS) walkDAG(displayPath, lsArg string, walkFn filepath.WalkFunc) error {
+	links, err := fsys.ls(lsArg)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		childDisplay := displayPath + "/" + link.Name
+		if link.Type == dagDirectory {
+			if err := fsys.walkDAG(childDisplay, lsArg+"/"+link.Name, walkFn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := walkFn(childDisplay, &memFileInfo{name: link.Name}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fsys *IPFSFS) ls(arg string) ([]dagLink, error) {
+	req, err := http.NewRequest(http.MethodPost, fsys.nodeAPI()+"/api/v0/ls?arg="+url.QueryEscape(arg), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := fsys.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ipfs: node API returned %s", resp.Status)
+	}
+
+	var result struct {
+		Objects []struct {
+			Links []dagLink `json:"Links"`
+		} `json:"Objects"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("ipfs: decoding ls response: %w", err)
+	}
+	if len(result.Objects) == 0 {
+		return nil, nil
+	}
+	return result.Objects[0].Links, nil
+}
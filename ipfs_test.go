@@ -0,0 +1,179 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+// stubDoer lets tests satisfy httpDoer without touching the network.
+type stubDoer struct {
+	do func(req *http.Request) (*http.Response, error)
+}
+
+func (s *stubDoer) Do(req *http.Request) (*http.Response, error) {
+	return s.do(req)
+}
+
+func stubResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Status:     http.StatusText(status),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestIsIPFSPath(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"ipfs URI", "ipfs://QmHash/readme.md", true},
+		{"local path", "/tmp/readme.md", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsIPFSPath(tt.path); got != tt.want {
+				t.Errorf("IsIPFSPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPFSFS_Open_FetchesThroughGateway(t *testing.T) {
+	// Arrange
+	var requestedURL string
+	fsys := &IPFSFS{
+		Gateway: "https://ipfs.io/ipfs/",
+		Client: &stubDoer{do: func(req *http.Request) (*http.Response, error) {
+			requestedURL = req.URL.String()
+			return stubResponse(http.StatusOK, "# Notes"), nil
+		}},
+	}
+
+	// Act
+	rc, err := fsys.Open("ipfs://QmHash/notes/readme.md")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("reading body failed: %v", err)
+	}
+
+	// Assert
+	if string(content) != "# Notes" {
+		t.Errorf("got %q, want %q", content, "# Notes")
+	}
+	wantURL := "https://ipfs.io/ipfs/QmHash/notes/readme.md"
+	if requestedURL != wantURL {
+		t.Errorf("requested %q, want %q", requestedURL, wantURL)
+	}
+}
+
+func TestIPFSFS_Open_NonOKStatusIsError(t *testing.T) {
+	fsys := &IPFSFS{
+		Client: &stubDoer{do: func(req *http.Request) (*http.Response, error) {
+			return stubResponse(http.StatusNotFound, ""), nil
+		}},
+	}
+
+	if _, err := fsys.Open("ipfs://QmMissing/readme.md"); err == nil {
+		t.Fatal("expected an error for a non-200 gateway response, got nil")
+	}
+}
+
+func TestIPFSFS_Pin_ReturnsCID(t *testing.T) {
+	// Arrange
+	var requestedPath string
+	fsys := &IPFSFS{
+		NodeAPI: "http://127.0.0.1:5001",
+		Client: &stubDoer{do: func(req *http.Request) (*http.Response, error) {
+			requestedPath = req.URL.Path
+			return stubResponse(http.StatusOK, `{"Name":"blob","Hash":"QmNewHash","Size":"7"}`), nil
+		}},
+	}
+
+	// Act
+	cid, err := fsys.Pin([]byte("# Notes"))
+	if err != nil {
+		t.Fatalf("Pin failed: %v", err)
+	}
+
+	// Assert
+	if cid != "QmNewHash" {
+		t.Errorf("got %q, want %q", cid, "QmNewHash")
+	}
+	if requestedPath != "/api/v0/add" {
+		t.Errorf("requested path %q, want %q", requestedPath, "/api/v0/add")
+	}
+}
+
+func TestIPFSFS_Unpin_UsesPinRmEndpoint(t *testing.T) {
+	var requestedURL string
+	fsys := &IPFSFS{
+		Client: &stubDoer{do: func(req *http.Request) (*http.Response, error) {
+			requestedURL = req.URL.String()
+			return stubResponse(http.StatusOK, `{"Pins":["QmHash"]}`), nil
+		}},
+	}
+
+	if err := fsys.Unpin("QmHash"); err != nil {
+		t.Fatalf("Unpin failed: %v", err)
+	}
+	if !strings.Contains(requestedURL, "/api/v0/pin/rm") || !strings.Contains(requestedURL, "QmHash") {
+		t.Errorf("requested %q, want it to hit pin/rm with the CID", requestedURL)
+	}
+}
+
+func TestIPFSFS_Walk_ListsMarkdownChildrenRecursively(t *testing.T) {
+	// Arrange: a root directory with one file and one subdirectory, each
+	// requiring its own ls call keyed by the path walked so far.
+	responses := map[string]string{
+		"QmRoot": `{"Objects":[{"Links":[
+			{"Name":"readme.md","Type":0},
+			{"Name":"notes","Type":1}
+		]}]}`,
+		"QmRoot/notes": `{"Objects":[{"Links":[
+			{"Name":"guide.md","Type":0}
+		]}]}`,
+	}
+
+	fsys := &IPFSFS{
+		Client: &stubDoer{do: func(req *http.Request) (*http.Response, error) {
+			arg := req.URL.Query().Get("arg")
+			body, ok := responses[arg]
+			if !ok {
+				t.Fatalf("unexpected ls arg %q", arg)
+			}
+			return stubResponse(http.StatusOK, body), nil
+		}},
+	}
+
+	// Act
+	var visited []string
+	err := fsys.Walk("ipfs://QmRoot", func(path string, info os.FileInfo, err error) error {
+		visited = append(visited, path)
+		return nil
+	})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	want := []string{"ipfs://QmRoot/readme.md", "ipfs://QmRoot/notes/guide.md"}
+	if len(visited) != len(want) {
+		t.Fatalf("got %v, want %v", visited, want)
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Errorf("got %v, want %v", visited, want)
+		}
+	}
+}
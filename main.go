@@ -1,29 +1,51 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
+	"github.com/fsnotify/fsnotify"
 )
 
+// prefKeyMarkdownExtensions is the fyne.Preferences key under which the
+// user's configured file extensions are persisted.
+const prefKeyMarkdownExtensions = "markdownExtensions"
+
+// selfWriteGuardWindow suppresses watcher reload/conflict handling for a
+// short period after the editor itself writes a file, so our own saves
+// don't look like external changes.
+const selfWriteGuardWindow = 300 * time.Millisecond
+
 type MarkdownEditor struct {
-	app           fyne.App
-	window        fyne.Window
-	currentFile   string
-	currentDir    string
-	editor        *widget.Entry
-	preview       *widget.RichText
-	previewScroll *container.Scroll
-	fileList      *widget.List
-	files         []string
-	isDirty       bool
+	app                fyne.App
+	window             fyne.Window
+	fs                 FileSystem
+	mainMenu           *fyne.MainMenu
+	saveMenuItem       *fyne.MenuItem
+	archiveMounted     bool
+	markdownExtensions []string
+	watcher            *Watcher
+	selfWriteGuard     map[string]time.Time
+	ipfsFS             *IPFSFS
+	currentFile        string
+	currentDir         string
+	editor             *widget.Entry
+	preview            *widget.RichText
+	previewScroll      *container.Scroll
+	fileList           *widget.List
+	files              []string
+	isDirty            bool
 }
 
 func main() {
@@ -32,13 +54,22 @@ func main() {
 	myWindow.Resize(fyne.NewSize(1200, 800))
 
 	editor := &MarkdownEditor{
-		app:    myApp,
-		window: myWindow,
-		files:  []string{},
+		app:                myApp,
+		window:             myWindow,
+		fs:                 OSFS{},
+		markdownExtensions: loadMarkdownExtensions(myApp),
+		selfWriteGuard:     make(map[string]time.Time),
+		ipfsFS:             NewIPFSFS(),
+		files:              []string{},
 	}
 
 	editor.setupUI()
 
+	myWindow.SetCloseIntercept(func() {
+		editor.stopWatching()
+		myWindow.Close()
+	})
+
 	// Check if a file was provided via command line
 	if len(os.Args) > 1 {
 		filePath := os.Args[1]
@@ -64,6 +95,16 @@ func main() {
 	myWindow.ShowAndRun()
 }
 
+// loadMarkdownExtensions reads the user's configured extensions from
+// preferences, falling back to DefaultMarkdownExtensions the first time
+// the app runs.
+func loadMarkdownExtensions(a fyne.App) []string {
+	if stored := a.Preferences().StringList(prefKeyMarkdownExtensions); len(stored) > 0 {
+		return stored
+	}
+	return append([]string(nil), DefaultMarkdownExtensions...)
+}
+
 func (e *MarkdownEditor) setupUI() {
 	// Create editor widget with better sizing
 	e.editor = widget.NewMultiLineEntry()
@@ -147,11 +188,17 @@ func (e *MarkdownEditor) setupUI() {
 func (e *MarkdownEditor) setupMenu() {
 	// File menu
 	openFileItem := fyne.NewMenuItem("Open File...", func() {
-		dialog.ShowFileOpen(func(file fyne.URIReadCloser, err error) {
+		openDialog := dialog.NewFileOpen(func(file fyne.URIReadCloser, err error) {
 			if err != nil || file == nil {
 				return
 			}
 			filePath := file.URI().Path()
+			if IsArchiveFile(filePath) {
+				e.openArchive(filePath)
+				return
+			}
+
+			e.mountFileSystem(OSFS{})
 			e.currentDir = filepath.Dir(filePath)
 			e.loadDirectory(e.currentDir)
 			for i, f := range e.files {
@@ -161,6 +208,8 @@ func (e *MarkdownEditor) setupMenu() {
 				}
 			}
 		}, e.window)
+		openDialog.SetFilter(e.openFileFilter())
+		openDialog.Show()
 	})
 
 	openDirItem := fyne.NewMenuItem("Open Directory...", func() {
@@ -168,6 +217,7 @@ func (e *MarkdownEditor) setupMenu() {
 			if err != nil || dir == nil {
 				return
 			}
+			e.mountFileSystem(OSFS{})
 			e.currentDir = dir.Path()
 			e.loadDirectory(dir.Path())
 		}, e.window)
@@ -180,6 +230,28 @@ func (e *MarkdownEditor) setupMenu() {
 	saveItem := fyne.NewMenuItem("Save", func() {
 		e.saveCurrentFile()
 	})
+	e.saveMenuItem = saveItem
+
+	saveAsItem := fyne.NewMenuItem("Save As...", func() {
+		e.saveFileAs()
+	})
+
+	fileTypesItem := fyne.NewMenuItem("File Types...", func() {
+		e.showFileTypesDialog()
+	})
+
+	exportItem := fyne.NewMenuItem("Export", nil)
+	exportItem.ChildMenu = fyne.NewMenu("",
+		fyne.NewMenuItem("Export to HTML...", func() {
+			e.exportCurrentDocument(ExportHTML, "html")
+		}),
+		fyne.NewMenuItem("Export to PDF...", func() {
+			e.exportCurrentDocument(ExportPDF, "pdf")
+		}),
+		fyne.NewMenuItem("Export Collection to EPUB...", func() {
+			e.exportCollectionToEPUB()
+		}),
+	)
 
 	fileMenu := fyne.NewMenu("File",
 		openFileItem,
@@ -187,23 +259,41 @@ func (e *MarkdownEditor) setupMenu() {
 		fyne.NewMenuItemSeparator(),
 		newFileItem,
 		saveItem,
+		saveAsItem,
+		fyne.NewMenuItemSeparator(),
+		fileTypesItem,
+		exportItem,
 	)
 
 	// Edit menu with standard shortcuts
 	editMenu := fyne.NewMenu("Edit")
 
+	// Remote menu for content-addressed (IPFS) sources
+	openIPFSItem := fyne.NewMenuItem("Open IPFS CID...", func() {
+		e.promptOpenIPFS()
+	})
+	pinItem := fyne.NewMenuItem("Pin Current Document", func() {
+		e.pinCurrentDocument()
+	})
+	unpinItem := fyne.NewMenuItem("Unpin...", func() {
+		e.promptUnpin()
+	})
+	remoteMenu := fyne.NewMenu("Remote", openIPFSItem, pinItem, unpinItem)
+
 	// Main menu
 	mainMenu := fyne.NewMainMenu(
 		fileMenu,
 		editMenu,
+		remoteMenu,
 	)
 
+	e.mainMenu = mainMenu
 	e.window.SetMainMenu(mainMenu)
 	e.window.SetMaster()
 }
 
 func (e *MarkdownEditor) loadDirectory(dirPath string) {
-	files, err := FindMarkdownFiles(dirPath)
+	files, err := FindMarkdownFiles(e.fs, dirPath, e.markdownExtensions)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("error loading directory: %v", err), e.window)
 		return
@@ -215,6 +305,141 @@ func (e *MarkdownEditor) loadDirectory(dirPath string) {
 	if len(e.files) > 0 {
 		e.fileList.Select(0)
 	}
+
+	e.startWatching(dirPath)
+}
+
+// startWatching observes dirPath for external changes, replacing any
+// previous watcher. Watching only applies to real disk directories; it is
+// a no-op for in-memory or archive-backed filesystems.
+func (e *MarkdownEditor) startWatching(dirPath string) {
+	e.stopWatching()
+
+	if _, ok := e.fs.(OSFS); !ok {
+		return
+	}
+
+	w, err := NewWatcher(dirPath, func(path string, op fsnotify.Op) {
+		e.handleWatchEvent(path, op)
+	})
+	if err != nil {
+		return
+	}
+	e.watcher = w
+}
+
+// stopWatching tears down the current watcher goroutine, if any.
+func (e *MarkdownEditor) stopWatching() {
+	if e.watcher == nil {
+		return
+	}
+	e.watcher.Close()
+	e.watcher = nil
+}
+
+// handleWatchEvent is invoked from the watcher's own goroutine for each
+// debounced filesystem event under the watched directory. Everything it
+// touches — editor state and Fyne widgets/dialogs alike — must only be
+// mutated from the main goroutine, so the whole handler runs via
+// fyne.Do.
+func (e *MarkdownEditor) handleWatchEvent(path string, op fsnotify.Op) {
+	fyne.Do(func() {
+		if guardedAt, ok := e.selfWriteGuard[path]; ok {
+			delete(e.selfWriteGuard, path)
+			if time.Since(guardedAt) < selfWriteGuardWindow {
+				return
+			}
+		}
+
+		switch {
+		case op&(fsnotify.Remove|fsnotify.Rename) != 0:
+			e.handleFileRemoved(path)
+		case op&fsnotify.Create != 0:
+			e.handleFileCreated(path)
+		case op&fsnotify.Write != 0:
+			e.handleFileWritten(path)
+		}
+	})
+}
+
+func (e *MarkdownEditor) handleFileWritten(path string) {
+	if path != e.currentFile {
+		return
+	}
+	if !e.isDirty {
+		e.doLoadFile(path)
+		return
+	}
+	e.showExternalChangeConflict(path)
+}
+
+func (e *MarkdownEditor) handleFileCreated(path string) {
+	if !IsMarkdownFile(path, e.markdownExtensions) {
+		return
+	}
+	for _, f := range e.files {
+		if f == path {
+			return
+		}
+	}
+	e.files = append(e.files, path)
+	sort.Strings(e.files)
+	e.fileList.Refresh()
+}
+
+func (e *MarkdownEditor) handleFileRemoved(path string) {
+	for i, f := range e.files {
+		if f == path {
+			e.files = append(e.files[:i], e.files[i+1:]...)
+			e.fileList.Refresh()
+			break
+		}
+	}
+	if path == e.currentFile {
+		dialog.ShowInformation("File Removed", fmt.Sprintf("%s was removed on disk.", filepath.Base(path)), e.window)
+	}
+}
+
+// showExternalChangeConflict offers the user a three-way choice when a
+// file with unsaved edits changes on disk.
+func (e *MarkdownEditor) showExternalChangeConflict(path string) {
+	diskContent, err := LoadFileContent(e.fs, path)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error reading changed file: %v", err), e.window)
+		return
+	}
+
+	message := widget.NewLabel(fmt.Sprintf("%s changed on disk while you have unsaved edits.", filepath.Base(path)))
+
+	var conflictDialog dialog.Dialog
+	keepMineBtn := widget.NewButton("Keep Mine", func() {
+		conflictDialog.Hide()
+	})
+	loadDiskBtn := widget.NewButton("Load Disk Version", func() {
+		conflictDialog.Hide()
+		e.doLoadFile(path)
+	})
+	showDiffBtn := widget.NewButton("Show Diff", func() {
+		conflictDialog.Hide()
+		e.showDiff(path, e.editor.Text, diskContent)
+	})
+
+	content := container.NewVBox(message, container.NewHBox(keepMineBtn, loadDiskBtn, showDiffBtn))
+	conflictDialog = dialog.NewCustom("External Change Detected", "Close", content, e.window)
+	conflictDialog.Show()
+}
+
+// showDiff renders a simple line-diff between the in-editor buffer and the
+// version currently on disk.
+func (e *MarkdownEditor) showDiff(path, mine, disk string) {
+	diffView := widget.NewMultiLineEntry()
+	diffView.SetText(FormatDiff(LineDiff(mine, disk)))
+	diffView.Disable()
+
+	diffDialog := dialog.NewCustom(fmt.Sprintf("Diff: %s", filepath.Base(path)), "Close",
+		container.NewScroll(diffView), e.window)
+	diffDialog.Resize(fyne.NewSize(700, 500))
+	diffDialog.Show()
 }
 
 func (e *MarkdownEditor) loadFile(filePath string) {
@@ -233,7 +458,7 @@ func (e *MarkdownEditor) loadFile(filePath string) {
 }
 
 func (e *MarkdownEditor) doLoadFile(filePath string) {
-	content, err := LoadFileContent(filePath)
+	content, err := LoadFileContent(e.fs, filePath)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("error reading file: %v", err), e.window)
 		return
@@ -247,13 +472,209 @@ func (e *MarkdownEditor) doLoadFile(filePath string) {
 	e.window.SetTitle(fmt.Sprintf("Markdown Viewer/Editor - %s", filepath.Base(filePath)))
 }
 
+// openFileFilter returns the storage.FileFilter the "Open File..." dialog
+// should use: the user's configured markdown extensions plus the archive
+// formats openArchive knows how to mount. storage.NewExtensionFileFilter
+// compares against uri.Extension(), which is only the last dot-separated
+// component, so it can never match the double extensions ".tar.gz" and
+// ".tar.bz2" — archiveSuffixFilter defers to IsArchiveFile, which parses
+// those correctly, instead.
+func (e *MarkdownEditor) openFileFilter() storage.FileFilter {
+	return &archiveSuffixFilter{extensionFilter: storage.NewExtensionFileFilter(e.markdownExtensions)}
+}
+
+// archiveSuffixFilter matches files against extensionFilter or, failing
+// that, against IsArchiveFile.
+type archiveSuffixFilter struct {
+	extensionFilter storage.FileFilter
+}
+
+func (f *archiveSuffixFilter) Matches(u fyne.URI) bool {
+	return f.extensionFilter.Matches(u) || IsArchiveFile(u.Name())
+}
+
+// mountFileSystem switches the editor to fsys, clearing any previously
+// mounted read-only archive.
+func (e *MarkdownEditor) mountFileSystem(fsys FileSystem) {
+	e.fs = fsys
+	e.archiveMounted = false
+	e.updateSaveAvailability()
+}
+
+// openArchive mounts archivePath as a read-only FileSystem and populates
+// the file list with the markdown documents it contains.
+func (e *MarkdownEditor) openArchive(archivePath string) {
+	archiveFS, err := OpenArchive(archivePath)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error opening archive: %v", err), e.window)
+		return
+	}
+
+	e.fs = archiveFS
+	e.archiveMounted = true
+	e.updateSaveAvailability()
+	e.currentFile = ""
+	e.isDirty = false
+	e.currentDir = "/"
+	e.loadDirectory(e.currentDir)
+
+	dialog.ShowInformation("Archive Mounted",
+		fmt.Sprintf("%s is open read-only; saving is disabled while an archive is mounted.", filepath.Base(archivePath)),
+		e.window)
+}
+
+// promptOpenIPFS asks the user for a directory CID and mounts it as a
+// read-through, content-addressed source.
+func (e *MarkdownEditor) promptOpenIPFS() {
+	cidEntry := widget.NewEntry()
+	cidEntry.SetPlaceHolder("CID, e.g. QmSomeDirectoryHash")
+
+	formItems := []*widget.FormItem{
+		widget.NewFormItem("CID", cidEntry),
+	}
+
+	dialog.ShowForm("Open IPFS Directory", "Open", "Cancel", formItems, func(confirmed bool) {
+		if !confirmed || strings.TrimSpace(cidEntry.Text) == "" {
+			return
+		}
+		e.openIPFSDirectory(strings.TrimSpace(cidEntry.Text))
+	}, e.window)
+}
+
+// openIPFSDirectory mounts cid as the active FileSystem and lists its
+// markdown children.
+func (e *MarkdownEditor) openIPFSDirectory(cid string) {
+	e.mountFileSystem(e.ipfsFS)
+	e.currentFile = ""
+	e.isDirty = false
+	e.currentDir = ipfsScheme + cid
+	e.loadDirectory(e.currentDir)
+}
+
+// pinCurrentDocument saves the in-editor buffer to the configured IPFS
+// node and rewrites currentFile to the CID it was pinned under.
+func (e *MarkdownEditor) pinCurrentDocument() {
+	if e.currentFile == "" {
+		dialog.ShowInformation("No File", "Please open or create a file first", e.window)
+		return
+	}
+
+	cid, err := e.ipfsFS.Pin([]byte(e.editor.Text))
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error pinning to ipfs: %v", err), e.window)
+		return
+	}
+
+	e.currentFile = ipfsScheme + cid
+	e.isDirty = false
+	e.window.SetTitle(fmt.Sprintf("Markdown Viewer/Editor - %s", filepath.Base(e.currentFile)))
+	dialog.ShowInformation("Pinned", fmt.Sprintf("Saved as %s", e.currentFile), e.window)
+}
+
+// promptUnpin asks the user for a CID and unpins it from the configured
+// IPFS node.
+func (e *MarkdownEditor) promptUnpin() {
+	cidEntry := widget.NewEntry()
+	cidEntry.SetPlaceHolder("CID to unpin")
+
+	formItems := []*widget.FormItem{
+		widget.NewFormItem("CID", cidEntry),
+	}
+
+	dialog.ShowForm("Unpin", "Unpin", "Cancel", formItems, func(confirmed bool) {
+		if !confirmed || strings.TrimSpace(cidEntry.Text) == "" {
+			return
+		}
+		if err := e.ipfsFS.Unpin(strings.TrimSpace(cidEntry.Text)); err != nil {
+			dialog.ShowError(fmt.Errorf("error unpinning: %v", err), e.window)
+			return
+		}
+		dialog.ShowInformation("Unpinned", "CID unpinned.", e.window)
+	}, e.window)
+}
+
+// updateSaveAvailability greys out the Save menu item while a read-only
+// archive is mounted.
+func (e *MarkdownEditor) updateSaveAvailability() {
+	e.saveMenuItem.Disabled = e.archiveMounted
+	e.window.SetMainMenu(e.mainMenu)
+}
+
+// saveFileAs lets the user write the current buffer to a new location,
+// regardless of which FileSystem is currently mounted for browsing.
+func (e *MarkdownEditor) saveFileAs() {
+	saveDialog := dialog.NewFileSave(func(file fyne.URIWriteCloser, err error) {
+		if err != nil || file == nil {
+			return
+		}
+		defer file.Close()
+
+		path := file.URI().Path()
+		e.selfWriteGuard[path] = time.Now()
+
+		if _, err := file.Write([]byte(e.editor.Text)); err != nil {
+			dialog.ShowError(fmt.Errorf("error saving file: %v", err), e.window)
+			return
+		}
+
+		e.mountFileSystem(OSFS{})
+		e.currentFile = path
+		e.isDirty = false
+		e.window.SetTitle(fmt.Sprintf("Markdown Viewer/Editor - %s", filepath.Base(path)))
+
+		e.currentDir = filepath.Dir(path)
+		e.loadDirectory(e.currentDir)
+		for i, f := range e.files {
+			if f == path {
+				e.fileList.Select(i)
+				break
+			}
+		}
+	}, e.window)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter(e.markdownExtensions))
+	saveDialog.Show()
+}
+
+// showFileTypesDialog lets the user edit and persist the set of file
+// extensions treated as markdown.
+func (e *MarkdownEditor) showFileTypesDialog() {
+	extensionsEntry := widget.NewEntry()
+	extensionsEntry.SetText(strings.Join(e.markdownExtensions, ", "))
+
+	formItems := []*widget.FormItem{
+		widget.NewFormItem("Extensions", extensionsEntry),
+	}
+
+	dialog.ShowForm("File Types", "Save", "Cancel", formItems, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		e.markdownExtensions = ParseExtensionList(extensionsEntry.Text)
+		e.app.Preferences().SetStringList(prefKeyMarkdownExtensions, e.markdownExtensions)
+
+		if e.currentDir != "" {
+			e.loadDirectory(e.currentDir)
+		}
+	}, e.window)
+}
+
 func (e *MarkdownEditor) saveCurrentFile() {
+	if e.archiveMounted {
+		dialog.ShowInformation("Read-Only Archive", "Saving is disabled while an archive is mounted.", e.window)
+		return
+	}
 	if e.currentFile == "" {
 		dialog.ShowInformation("No File", "Please open or create a file first", e.window)
 		return
 	}
+	if IsIPFSPath(e.currentFile) {
+		e.pinCurrentDocument()
+		return
+	}
 
-	err := SaveFileContent(e.currentFile, e.editor.Text)
+	e.selfWriteGuard[e.currentFile] = time.Now()
+	err := SaveFileContent(e.fs, e.currentFile, e.editor.Text)
 	if err != nil {
 		dialog.ShowError(fmt.Errorf("error saving file: %v", err), e.window)
 		return
@@ -263,11 +684,80 @@ func (e *MarkdownEditor) saveCurrentFile() {
 	dialog.ShowInformation("Saved", fmt.Sprintf("File saved: %s", filepath.Base(e.currentFile)), e.window)
 }
 
+// exportCurrentDocument renders the current buffer's source file to format,
+// prompting the user for a destination path.
+func (e *MarkdownEditor) exportCurrentDocument(format ExportFormat, ext string) {
+	if e.currentFile == "" {
+		dialog.ShowInformation("No File", "Please open a file first", e.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(file fyne.URIWriteCloser, err error) {
+		if err != nil || file == nil {
+			return
+		}
+		outPath := file.URI().Path()
+		file.Close()
+
+		converter := &Converter{BasePath: e.currentFile, OutputPath: outPath, Format: format}
+		e.runExport(converter)
+	}, e.window)
+	base := strings.TrimSuffix(filepath.Base(e.currentFile), filepath.Ext(e.currentFile))
+	saveDialog.SetFileName(base + "." + ext)
+	saveDialog.Show()
+}
+
+// exportCollectionToEPUB bundles every file currently listed in the file
+// list into a single EPUB, prompting the user for a destination path.
+func (e *MarkdownEditor) exportCollectionToEPUB() {
+	if len(e.files) == 0 {
+		dialog.ShowInformation("No Files", "Please open a directory or archive first", e.window)
+		return
+	}
+
+	saveDialog := dialog.NewFileSave(func(file fyne.URIWriteCloser, err error) {
+		if err != nil || file == nil {
+			return
+		}
+		outPath := file.URI().Path()
+		file.Close()
+
+		converter := &Converter{
+			BasePath:   e.currentDir,
+			OutputPath: outPath,
+			Format:     ExportEPUB,
+			Files:      append([]string(nil), e.files...),
+		}
+		e.runExport(converter)
+	}, e.window)
+	saveDialog.SetFileName("collection.epub")
+	saveDialog.Show()
+}
+
+// runExport executes converter against the editor's current FileSystem,
+// surfacing a targeted dialog when wkhtmltopdf is missing and a generic
+// one for any other failure.
+func (e *MarkdownEditor) runExport(converter *Converter) {
+	if err := converter.Convert(e.fs); err != nil {
+		if errors.Is(err, errWkhtmltopdfMissing) {
+			dialog.ShowError(errors.New("wkhtmltopdf is required for PDF export but was not found in PATH"), e.window)
+			return
+		}
+		dialog.ShowError(fmt.Errorf("error exporting: %v", err), e.window)
+		return
+	}
+	dialog.ShowInformation("Exported", fmt.Sprintf("Exported to %s", filepath.Base(converter.OutputPath)), e.window)
+}
+
 func (e *MarkdownEditor) createNewFile() {
 	if e.currentDir == "" {
 		dialog.ShowError(fmt.Errorf("please open a directory first"), e.window)
 		return
 	}
+	if IsIPFSPath(e.currentDir) {
+		dialog.ShowError(fmt.Errorf("creating files is not supported for IPFS-mounted directories"), e.window)
+		return
+	}
 
 	// Create filename entry
 	filenameEntry := widget.NewEntry()
@@ -295,7 +785,7 @@ func (e *MarkdownEditor) createNewFile() {
 		newFilePath := filepath.Join(e.currentDir, filename)
 
 		// Create empty file
-		err := CreateMarkdownFile(newFilePath)
+		err := CreateMarkdownFile(e.fs, newFilePath)
 		if err != nil {
 			dialog.ShowError(fmt.Errorf("error creating file: %v", err), e.window)
 			return
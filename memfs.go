@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory FileSystem, primarily useful for tests so they no
+// longer need to touch disk via t.TempDir.
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string]*memFileData
+	// dirs records directories that exist but may be empty. A directory
+	// containing a file is already implied by that file's path, so this
+	// only needs to track the otherwise-invisible empty case.
+	dirs map[string]struct{}
+}
+
+type memFileData struct {
+	data    []byte
+	modTime time.Time
+}
+
+// NewMemFS returns an empty in-memory filesystem.
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string]*memFileData), dirs: make(map[string]struct{})}
+}
+
+// Mkdir registers dir as an existing directory, so Walk and Stat can tell
+// it apart from a path that was never created, even before any file is
+// written under it.
+func (m *MemFS) Mkdir(dir string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dirs[filepath.Clean(dir)] = struct{}{}
+	return nil
+}
+
+// MkdirAll registers dir and every parent leading to it as existing
+// directories.
+func (m *MemFS) MkdirAll(dir string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for d := filepath.Clean(dir); d != "." && d != string(filepath.Separator); d = filepath.Dir(d) {
+		m.dirs[d] = struct{}{}
+	}
+	return nil
+}
+
+func (m *MemFS) Open(name string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, ok := m.files[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+func (m *MemFS) Create(name string) (io.WriteCloser, error) {
+	return &memFileWriter{fs: m, name: name}, nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for dir := filepath.Dir(name); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		if _, ok := m.files[dir]; ok {
+			return &os.PathError{Op: "write", Path: name, Err: fmt.Errorf("not a directory")}
+		}
+	}
+
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	m.files[name] = &memFileData{data: buf, modTime: time.Now()}
+	return nil
+}
+
+func (m *MemFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if f, ok := m.files[name]; ok {
+		return &memFileInfo{name: filepath.Base(name), size: int64(len(f.data)), modTime: f.modTime}, nil
+	}
+	if m.hasDir(name) {
+		return &memFileInfo{name: filepath.Base(name), isDir: true}, nil
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+// Walk visits every file stored under root in lexical order. Unlike
+// filepath.Walk, it does not call walkFn for directories.
+func (m *MemFS) Walk(root string, walkFn filepath.WalkFunc) error {
+	m.mu.Lock()
+	_, isFile := m.files[filepath.Clean(root)]
+	exists := isFile || m.hasDir(root)
+	paths := m.pathsUnder(root)
+	m.mu.Unlock()
+
+	if !exists {
+		return &os.PathError{Op: "walk", Path: root, Err: os.ErrNotExist}
+	}
+
+	sort.Strings(paths)
+	for _, p := range paths {
+		m.mu.Lock()
+		f := m.files[p]
+		m.mu.Unlock()
+		if err := walkFn(p, &memFileInfo{name: filepath.Base(p), size: int64(len(f.data)), modTime: f.modTime}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MemFS) pathsUnder(root string) []string {
+	root = filepath.Clean(root)
+	var paths []string
+	for p := range m.files {
+		if root == "." || root == string(filepath.Separator) || p == root || strings.HasPrefix(p, root+string(filepath.Separator)) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+func (m *MemFS) hasDir(dir string) bool {
+	dir = filepath.Clean(dir)
+	if dir == "." || dir == string(filepath.Separator) {
+		return true
+	}
+	if _, ok := m.dirs[dir]; ok {
+		return true
+	}
+	for p := range m.files {
+		if strings.HasPrefix(p, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+type memFileWriter struct {
+	fs   *MemFS
+	name string
+	buf  bytes.Buffer
+}
+
+func (w *memFileWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *memFileWriter) Close() error {
+	return w.fs.WriteFile(w.name, w.buf.Bytes(), 0644)
+}
+
+type memFileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+func (fi *memFileInfo) Name() string       { return fi.name }
+func (fi *memFileInfo) Size() int64        { return fi.size }
+func (fi *memFileInfo) Mode() os.FileMode  { return 0644 }
+func (fi *memFileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *memFileInfo) IsDir() bool        { return fi.isDir }
+func (fi *memFileInfo) Sys() interface{}   { return nil }
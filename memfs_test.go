@@ -0,0 +1,124 @@
+package main
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestMemFS_WriteThenOpen_RoundTrips(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.WriteFile("/docs/a.md", []byte("hello"), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	f, err := fsys.Open("/docs/a.md")
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("got %q, want %q", string(data), "hello")
+	}
+}
+
+func TestMemFS_Open_NonExistent_ReturnsError(t *testing.T) {
+	if _, err := NewMemFS().Open("/missing.md"); err == nil {
+		t.Error("expected error for missing file, got nil")
+	}
+}
+
+func TestMemFS_Create_WritesOnClose(t *testing.T) {
+	fsys := NewMemFS()
+	w, err := fsys.Create("/docs/b.md")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := w.Write([]byte("content")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	content, err := LoadFileContent(fsys, "/docs/b.md")
+	if err != nil {
+		t.Fatalf("LoadFileContent failed: %v", err)
+	}
+	if content != "content" {
+		t.Errorf("got %q, want %q", content, "content")
+	}
+}
+
+func TestMemFS_Stat_ReportsSizeAndDir(t *testing.T) {
+	fsys := NewMemFS()
+	_ = fsys.WriteFile("/docs/c.md", []byte("1234"), 0644)
+
+	fi, err := fsys.Stat("/docs/c.md")
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if fi.IsDir() || fi.Size() != 4 {
+		t.Errorf("got isDir=%v size=%d, want isDir=false size=4", fi.IsDir(), fi.Size())
+	}
+
+	dirInfo, err := fsys.Stat("/docs")
+	if err != nil {
+		t.Fatalf("Stat on directory failed: %v", err)
+	}
+	if !dirInfo.IsDir() {
+		t.Error("expected /docs to report as a directory")
+	}
+}
+
+func TestMemFS_Mkdir_RegistersEmptyDirectory(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.Mkdir("/empty"); err != nil {
+		t.Fatalf("Mkdir failed: %v", err)
+	}
+
+	fi, err := fsys.Stat("/empty")
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if !fi.IsDir() {
+		t.Error("expected /empty to report as a directory")
+	}
+
+	if err := fsys.Walk("/empty", func(path string, info os.FileInfo, err error) error {
+		t.Errorf("expected no entries, got %q", path)
+		return nil
+	}); err != nil {
+		t.Errorf("Walk on an empty but registered directory should not error: %v", err)
+	}
+}
+
+func TestMemFS_MkdirAll_RegistersEveryParent(t *testing.T) {
+	fsys := NewMemFS()
+	if err := fsys.MkdirAll("/a/b/c"); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+
+	for _, dir := range []string{"/a", "/a/b", "/a/b/c"} {
+		fi, err := fsys.Stat(dir)
+		if err != nil {
+			t.Fatalf("Stat(%q) failed: %v", dir, err)
+		}
+		if !fi.IsDir() {
+			t.Errorf("expected %q to report as a directory", dir)
+		}
+	}
+}
+
+func TestMemFS_Walk_NonExistentDirectory_ReturnsError(t *testing.T) {
+	if err := NewMemFS().Walk("/nonexistent", func(path string, info os.FileInfo, err error) error {
+		return nil
+	}); err == nil {
+		t.Error("expected an error for a non-existent directory, got nil")
+	}
+}
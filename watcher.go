@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce coalesces bursts of OS events (e.g. an editor-triggered
+// write that fires several fsnotify events) into a single callback.
+const watchDebounce = 200 * time.Millisecond
+
+// Watcher observes a directory on disk and delivers debounced file events
+// to onEvent until Close is called.
+type Watcher struct {
+	fsWatcher *fsnotify.Watcher
+	done      chan struct{}
+
+	// onEvent is called from the watcher's own goroutine for each
+	// coalesced path/operation pair. It is set once by NewWatcher before
+	// the goroutine starts, so reads and writes never race.
+	onEvent func(path string, op fsnotify.Op)
+}
+
+// NewWatcher starts watching dir and every subdirectory beneath it,
+// reporting events to onEvent. fsnotify's backends (inotify, kqueue, FEN)
+// are non-recursive, so each subdirectory needs its own explicit Add; new
+// subdirectories created later are picked up by run as they appear.
+func NewWatcher(dir string, onEvent func(path string, op fsnotify.Op)) (*Watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fsWatcher.Add(path)
+		}
+		return nil
+	})
+	if err != nil {
+		fsWatcher.Close()
+		return nil, err
+	}
+
+	w := &Watcher{fsWatcher: fsWatcher, done: make(chan struct{}), onEvent: onEvent}
+	go w.run()
+	return w, nil
+}
+
+func (w *Watcher) run() {
+	pending := make(map[string]fsnotify.Op)
+	var timer *time.Timer
+	var timerC <-chan time.Time
+
+	flush := func() {
+		for path, op := range pending {
+			if w.onEvent != nil {
+				w.onEvent(path, op)
+			}
+		}
+		pending = make(map[string]fsnotify.Op)
+	}
+
+	for {
+		select {
+		case <-w.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		case event, ok := <-w.fsWatcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.fsWatcher.Add(event.Name)
+				}
+			}
+			pending[event.Name] |= event.Op
+			if timer == nil {
+				timer = time.NewTimer(watchDebounce)
+				timerC = timer.C
+			} else {
+				timer.Reset(watchDebounce)
+			}
+
+		case <-timerC:
+			flush()
+			timer = nil
+			timerC = nil
+
+		case _, ok := <-w.fsWatcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// Close stops the watcher goroutine and releases the underlying OS
+// resources. Safe to call once; a nil Watcher is a no-op for callers that
+// check before calling.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsWatcher.Close()
+}
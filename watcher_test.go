@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const watchTestTimeout = 2 * time.Second
+
+func waitForEvent(t *testing.T, events <-chan string, want string) {
+	t.Helper()
+	deadline := time.After(watchTestTimeout)
+	for {
+		select {
+		case got := <-events:
+			if got == want {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for event on %q", want)
+		}
+	}
+}
+
+func TestWatcher_SubdirectoryWrite_IsReported(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+	subDir := filepath.Join(root, "sub")
+	if err := os.Mkdir(subDir, 0755); err != nil {
+		t.Fatalf("failed to create subdirectory: %v", err)
+	}
+	subFile := filepath.Join(subDir, "note.md")
+	if err := os.WriteFile(subFile, []byte("initial"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	events := make(chan string, 16)
+	w, err := NewWatcher(root, func(path string, op fsnotify.Op) {
+		if op&fsnotify.Write != 0 {
+			events <- path
+		}
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	defer w.Close()
+
+	// Act
+	if err := os.WriteFile(subFile, []byte("changed"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// Assert
+	waitForEvent(t, events, subFile)
+}
+
+func TestWatcher_NewSubdirectory_IsWatched(t *testing.T) {
+	// Arrange
+	root := t.TempDir()
+
+	events := make(chan string, 16)
+	w, err := NewWatcher(root, func(path string, op fsnotify.Op) {
+		if op&fsnotify.Write != 0 {
+			events <- path
+		}
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+	defer w.Close()
+
+	newDir := filepath.Join(root, "new")
+	if err := os.Mkdir(newDir, 0755); err != nil {
+		t.Fatalf("failed to create new subdirectory: %v", err)
+	}
+	// Give the watcher's own goroutine time to observe the Create event
+	// and Add the new directory before anything is written into it.
+	time.Sleep(100 * time.Millisecond)
+
+	newFile := filepath.Join(newDir, "note.md")
+	if err := os.WriteFile(newFile, []byte("content"), 0644); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	// Act
+	if err := os.WriteFile(newFile, []byte("changed"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// Assert
+	waitForEvent(t, events, newFile)
+}